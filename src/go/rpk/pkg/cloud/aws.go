@@ -0,0 +1,205 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	awsMetadataBaseURL = "http://169.254.169.254/latest"
+	awsTokenTTLSeconds = 21600
+
+	awsTokenTTLHeader = "X-aws-ec2-metadata-token-ttl-seconds"
+	awsTokenHeader    = "X-aws-ec2-metadata-token"
+)
+
+// errIMDSv2Unreachable marks a failure to get a token from the IMDSv2
+// endpoint that isn't an explicit auth rejection (connection error, or the
+// endpoint not existing at all on older instances). Only this case should
+// fall back to unauthenticated IMDSv1; a 401/403 means the instance requires
+// IMDSv2 and must be surfaced as such.
+var errIMDSv2Unreachable = errors.New("IMDSv2 token endpoint unreachable")
+
+// awsVendor is the Vendor implementation for AWS EC2, detected through its
+// instance metadata service.
+//
+// Instances may be configured with HttpTokens=required, which rejects
+// unauthenticated IMDSv1 requests, so every request goes through IMDSv2
+// first: a session token is fetched once and cached for its TTL, then sent
+// on every subsequent metadata GET. Only a connection error or a missing
+// token endpoint (e.g. an older instance that doesn't have it at all) falls
+// back to unauthenticated IMDSv1; a 401/403 means the instance requires
+// IMDSv2 and must be reported as such rather than silently downgraded.
+type awsVendor struct {
+	baseURL string
+	client  *http.Client
+
+	mu       sync.Mutex
+	token    string
+	tokenExp time.Time
+}
+
+func (v *awsVendor) Name() string { return "aws" }
+
+func (v *awsVendor) InitWithContext(ctx context.Context) (InitializedVendor, error) {
+	if _, err := v.vmType(ctx); err != nil {
+		return nil, fmt.Errorf("aws vendor not available: %w", err)
+	}
+	return v, nil
+}
+
+func (v *awsVendor) VmType() (string, error) {
+	return v.vmType(context.Background())
+}
+
+func (v *awsVendor) vmType(ctx context.Context) (string, error) {
+	body, err := v.getMetadata(ctx, "meta-data/instance-type")
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+func (v *awsVendor) InstanceMetadata() (InstanceMetadata, error) {
+	ctx := context.Background()
+
+	doc, err := v.getMetadata(ctx, "dynamic/instance-identity/document")
+	if err != nil {
+		return InstanceMetadata{}, err
+	}
+	var identity struct {
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+	}
+	if err := json.Unmarshal(doc, &identity); err != nil {
+		return InstanceMetadata{}, fmt.Errorf("parsing instance identity document: %w", err)
+	}
+
+	// These are best-effort: a NIC-less metadata field or a throttled
+	// request shouldn't fail the whole lookup.
+	localIP, _ := v.getMetadata(ctx, "meta-data/local-ipv4")
+	publicIP, _ := v.getMetadata(ctx, "meta-data/public-ipv4")
+	macs, _ := v.getMetadata(ctx, "meta-data/network/interfaces/macs/")
+	lifecycle, _ := v.getMetadata(ctx, "meta-data/instance-life-cycle")
+
+	return InstanceMetadata{
+		Region:            identity.Region,
+		AvailabilityZone:  identity.AvailabilityZone,
+		InstanceID:        identity.InstanceID,
+		InstanceType:      identity.InstanceType,
+		LocalIP:           string(localIP),
+		PublicIP:          string(publicIP),
+		NetworkInterfaces: splitMetadataList(string(macs)),
+		IsSpot:            string(lifecycle) == "spot",
+	}, nil
+}
+
+func (v *awsVendor) httpClient() *http.Client {
+	if v.client == nil {
+		v.client = &http.Client{Timeout: 2 * time.Second}
+	}
+	return v.client
+}
+
+func (v *awsVendor) metadataBaseURL() string {
+	if v.baseURL != "" {
+		return v.baseURL
+	}
+	return awsMetadataBaseURL
+}
+
+// imdsToken returns a cached IMDSv2 session token, fetching and caching a
+// fresh one if needed. It returns errIMDSv2Unreachable if the token endpoint
+// couldn't be reached at all, so callers can fall back to IMDSv1.
+func (v *awsVendor) imdsToken(ctx context.Context) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.token != "" && time.Now().Before(v.tokenExp) {
+		return v.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, v.metadataBaseURL()+"/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(awsTokenTTLHeader, strconv.Itoa(awsTokenTTLSeconds))
+
+	resp, err := v.httpClient().Do(req)
+	if err != nil {
+		return "", errIMDSv2Unreachable
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", errors.New("IMDSv2 required")
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		// Older instances simply don't serve this path at all (it
+		// predates IMDSv2): treat that as "no IMDSv2 here" and let the
+		// caller fall back.
+		return "", errIMDSv2Unreachable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status from IMDSv2 token endpoint: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	v.token = string(body)
+	v.tokenExp = time.Now().Add(awsTokenTTLSeconds * time.Second)
+	return v.token, nil
+}
+
+// getMetadata fetches path from the metadata service, preferring IMDSv2 and
+// falling back to unauthenticated IMDSv1 only when the token endpoint is
+// unreachable outright.
+func (v *awsVendor) getMetadata(ctx context.Context, path string) ([]byte, error) {
+	token, err := v.imdsToken(ctx)
+	if err != nil && !errors.Is(err, errIMDSv2Unreachable) {
+		return nil, err
+	}
+	return v.get(ctx, v.metadataBaseURL()+"/"+path, token)
+}
+
+func (v *awsVendor) get(ctx context.Context, url, token string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set(awsTokenHeader, token)
+	}
+
+	resp, err := v.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func splitMetadataList(raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, "\n")
+}
@@ -0,0 +1,119 @@
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const fakeInstanceDocument = `{
+	"region": "us-west-2",
+	"availabilityZone": "us-west-2a",
+	"instanceId": "i-0123456789abcdef0",
+	"instanceType": "m5.xlarge"
+}`
+
+// newIMDSv2Server simulates an instance with HttpTokens=required: metadata
+// GETs are rejected unless they carry a token minted by the token endpoint.
+func newIMDSv2Server(t *testing.T) *httptest.Server {
+	const token = "fake-session-token"
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		require.Equal(t, "21600", r.Header.Get(awsTokenTTLHeader))
+		w.Write([]byte(token))
+	})
+	mux.HandleFunc("/latest/meta-data/instance-type", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(awsTokenHeader) != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("m5.xlarge"))
+	})
+	mux.HandleFunc("/latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(awsTokenHeader) != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(fakeInstanceDocument))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(awsTokenHeader) != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+	})
+	return httptest.NewServer(mux)
+}
+
+// newIMDSv1Server simulates an older instance with no IMDSv2 token endpoint
+// at all, so callers must fall back to unauthenticated requests.
+func newIMDSv1Server(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/latest/meta-data/instance-type", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("m5.xlarge"))
+	})
+	mux.HandleFunc("/latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fakeInstanceDocument))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+	return httptest.NewServer(mux)
+}
+
+func TestAWSVendorIMDSv2(t *testing.T) {
+	server := newIMDSv2Server(t)
+	defer server.Close()
+
+	v := &awsVendor{baseURL: server.URL + "/latest"}
+
+	initialized, err := v.InitWithContext(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "aws", initialized.Name())
+
+	vmType, err := initialized.VmType()
+	require.NoError(t, err)
+	require.Equal(t, "m5.xlarge", vmType)
+
+	metadata, err := initialized.InstanceMetadata()
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", metadata.Region)
+	require.Equal(t, "i-0123456789abcdef0", metadata.InstanceID)
+}
+
+func TestAWSVendorIMDSv1Fallback(t *testing.T) {
+	server := newIMDSv1Server(t)
+	defer server.Close()
+
+	v := &awsVendor{baseURL: server.URL + "/latest"}
+
+	initialized, err := v.InitWithContext(context.Background())
+	require.NoError(t, err)
+
+	vmType, err := initialized.VmType()
+	require.NoError(t, err)
+	require.Equal(t, "m5.xlarge", vmType)
+
+	metadata, err := initialized.InstanceMetadata()
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", metadata.Region)
+}
+
+func TestAWSVendorIMDSv2RequiredRejectsUnauthenticated(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	v := &awsVendor{baseURL: server.URL + "/latest"}
+
+	_, err := v.InitWithContext(context.Background())
+	require.ErrorContains(t, err, "IMDSv2 required")
+}
@@ -0,0 +1,62 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	// envVendorOverride lets operators on private clouds or in air-gapped
+	// environments skip metadata-service probing altogether and declare
+	// the vendor (or the lack of one) explicitly.
+	envVendorOverride = "RPK_CLOUD_VENDOR"
+
+	// envVendorMetadataOverride carries a JSON-encoded InstanceMetadata to
+	// report alongside envVendorOverride. It's optional; an unset or empty
+	// value yields a zero-value InstanceMetadata.
+	envVendorMetadataOverride = "RPK_CLOUD_VENDOR_METADATA"
+
+	// vendorNone tells rpk that it is not running on any known cloud
+	// vendor, skipping probing without having to fake one.
+	vendorNone = "none"
+)
+
+// staticVendor is an InitializedVendor built entirely from operator-supplied
+// overrides, with no metadata-service probing involved.
+type staticVendor struct {
+	name     string
+	metadata InstanceMetadata
+}
+
+func (v *staticVendor) Name() string { return v.name }
+
+func (v *staticVendor) VmType() (string, error) { return v.metadata.InstanceType, nil }
+
+func (v *staticVendor) InstanceMetadata() (InstanceMetadata, error) { return v.metadata, nil }
+
+// vendorFromOverride builds an InitializedVendor from envVendorOverride and
+// envVendorMetadataOverride, if set. ok reports whether an override was
+// requested at all; when it's false, callers should fall back to normal
+// probing. When the override is vendorNone, ok is true and err is non-nil,
+// since the operator has explicitly said no vendor should be detected.
+func vendorFromOverride() (vendor InitializedVendor, ok bool, err error) {
+	name, isSet := os.LookupEnv(envVendorOverride)
+	if !isSet {
+		return nil, false, nil
+	}
+	if name == vendorNone {
+		return nil, true, fmt.Errorf("cloud vendor detection disabled via %s=%s", envVendorOverride, vendorNone)
+	}
+
+	var metadata InstanceMetadata
+	if raw := os.Getenv(envVendorMetadataOverride); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			return nil, true, fmt.Errorf(
+				"invalid %s: %w", envVendorMetadataOverride, err,
+			)
+		}
+	}
+
+	return &staticVendor{name: name, metadata: metadata}, true, nil
+}
@@ -0,0 +1,102 @@
+package cloud
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVendorFromOverride(t *testing.T) {
+	tests := []struct {
+		name             string
+		vendorEnv        string
+		metadataEnv      string
+		expectOk         bool
+		expectErr        string
+		expectVendor     string
+		expectInstanceID string
+		expectSpot       bool
+	}{
+		{
+			name:     "no override falls back to probing",
+			expectOk: false,
+		},
+		{
+			name:      "none disables detection",
+			vendorEnv: vendorNone,
+			expectOk:  true,
+			expectErr: "cloud vendor detection disabled via RPK_CLOUD_VENDOR=none",
+		},
+		{
+			name:         "vendor override with no metadata",
+			vendorEnv:    "aws",
+			expectOk:     true,
+			expectVendor: "aws",
+		},
+		{
+			name:             "vendor override with metadata",
+			vendorEnv:        "gcp",
+			metadataEnv:      `{"InstanceID":"i-0123","IsSpot":true}`,
+			expectOk:         true,
+			expectVendor:     "gcp",
+			expectInstanceID: "i-0123",
+			expectSpot:       true,
+		},
+		{
+			name:        "invalid metadata JSON surfaces an error",
+			vendorEnv:   "aws",
+			metadataEnv: `not json`,
+			expectOk:    true,
+			expectErr:   "invalid RPK_CLOUD_VENDOR_METADATA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.vendorEnv != "" {
+				t.Setenv(envVendorOverride, tt.vendorEnv)
+			}
+			if tt.metadataEnv != "" {
+				t.Setenv(envVendorMetadataOverride, tt.metadataEnv)
+			}
+
+			vendor, ok, err := vendorFromOverride()
+			require.Equal(t, tt.expectOk, ok)
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			if !tt.expectOk {
+				return
+			}
+
+			require.Equal(t, tt.expectVendor, vendor.Name())
+			metadata, err := vendor.InstanceMetadata()
+			require.NoError(t, err)
+			require.Equal(t, tt.expectInstanceID, metadata.InstanceID)
+			require.Equal(t, tt.expectSpot, metadata.IsSpot)
+		})
+	}
+}
+
+func TestAvailableVendorOverridePrecedence(t *testing.T) {
+	// Even though "vendor1" would probe as available, the override must
+	// win without ever consulting the probed vendors.
+	t.Setenv(envVendorOverride, "aws")
+	t.Setenv(envVendorMetadataOverride, `{"Region":"us-west-2"}`)
+
+	vendor1 := &mockVendor{available: true, name: "vendor1"}
+	vendors := map[string]Vendor{"vendor1": vendor1}
+
+	availableVendor, err := availableVendorFrom(vendors)
+	require.NoError(t, err)
+	require.Equal(t, "aws", availableVendor.Name())
+
+	metadata, err := availableVendor.InstanceMetadata()
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", metadata.Region)
+
+	require.Zero(t, atomic.LoadInt32(&vendor1.initCount), "override must short-circuit probing entirely")
+}
@@ -0,0 +1,103 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Vendor is a cloud vendor that can be detected through a local metadata
+// service.
+type Vendor interface {
+	// Name is the vendor's name.
+	Name() string
+
+	// InitWithContext checks whether the current instance belongs to this
+	// vendor, probing its metadata service as needed, and returns an
+	// InitializedVendor on success. It must return promptly once ctx is
+	// cancelled, since it may be run alongside probes for other vendors.
+	InitWithContext(ctx context.Context) (InitializedVendor, error)
+}
+
+// InitializedVendor is a Vendor that has already been detected as the one
+// the current instance is running on.
+type InitializedVendor interface {
+	Name() string
+	VmType() (string, error)
+
+	// InstanceMetadata returns the vendor's view of the current instance.
+	InstanceMetadata() (InstanceMetadata, error)
+}
+
+// InstanceMetadata is the set of instance attributes rpk can glean from a
+// cloud vendor's metadata service, used to tailor defaults (e.g. tuners,
+// topic placement hints) to the instance rpk is running on.
+type InstanceMetadata struct {
+	Region            string
+	AvailabilityZone  string
+	InstanceID        string
+	InstanceType      string
+	LocalIP           string
+	PublicIP          string
+	NetworkInterfaces []string
+
+	// IsSpot reports whether the instance is a spot (AWS) or preemptible
+	// (GCP) instance, which may be reclaimed by the vendor at any time.
+	IsSpot bool
+}
+
+// AvailableVendor probes every known cloud vendor and returns the first one
+// that reports as available. An operator may bypass probing entirely by
+// setting RPK_CLOUD_VENDOR (and, optionally, RPK_CLOUD_VENDOR_METADATA); see
+// vendorFromOverride.
+func AvailableVendor() (InitializedVendor, error) {
+	return availableVendorFrom(knownVendors())
+}
+
+func knownVendors() map[string]Vendor {
+	return map[string]Vendor{
+		"aws": &awsVendor{},
+	}
+}
+
+type vendorProbe struct {
+	vendor InitializedVendor
+	err    error
+}
+
+// availableVendorFrom consults the RPK_CLOUD_VENDOR override before probing
+// anything, then probes every given vendor in parallel, passing each a
+// context that is cancelled as soon as one of them succeeds so the rest can
+// abandon their in-flight probes instead of each paying out its own
+// metadata-service timeout.
+func availableVendorFrom(vendors map[string]Vendor) (InitializedVendor, error) {
+	if vendor, ok, err := vendorFromOverride(); ok {
+		return vendor, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	probes := make(chan vendorProbe, len(vendors))
+	var wg sync.WaitGroup
+	for _, v := range vendors {
+		wg.Add(1)
+		go func(v Vendor) {
+			defer wg.Done()
+			vendor, err := v.InitWithContext(ctx)
+			probes <- vendorProbe{vendor, err}
+		}(v)
+	}
+	go func() {
+		wg.Wait()
+		close(probes)
+	}()
+
+	for p := range probes {
+		if p.err == nil {
+			cancel()
+			return p.vendor, nil
+		}
+	}
+	return nil, errors.New("The cloud vendor couldn't be detected")
+}
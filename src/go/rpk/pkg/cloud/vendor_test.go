@@ -1,7 +1,9 @@
 package cloud
 
 import (
+	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -11,13 +13,19 @@ type mockVendor struct {
 	available bool
 	name      string
 	vmType    string
+	metadata  InstanceMetadata
+
+	// initCount tracks how many times InitWithContext was called, so
+	// tests can assert a vendor was (or wasn't) actually probed.
+	initCount int32
 }
 
 func (v *mockVendor) Name() string {
 	return v.name
 }
 
-func (v *mockVendor) Init() (InitializedVendor, error) {
+func (v *mockVendor) InitWithContext(ctx context.Context) (InitializedVendor, error) {
+	atomic.AddInt32(&v.initCount, 1)
 	if !v.available {
 		return nil, fmt.Errorf("mockVendor '%s' is not available", v.name)
 	}
@@ -28,6 +36,10 @@ func (v *mockVendor) VmType() (string, error) {
 	return v.vmType, nil
 }
 
+func (v *mockVendor) InstanceMetadata() (InstanceMetadata, error) {
+	return v.metadata, nil
+}
+
 func TestAvailableVendor(t *testing.T) {
 	var (
 		name1 = "vendor1"
@@ -35,9 +47,9 @@ func TestAvailableVendor(t *testing.T) {
 		name3 = "vendor3"
 	)
 	vendors := make(map[string]Vendor)
-	vendors[name1] = &mockVendor{false, name1, ""}
-	vendors[name2] = &mockVendor{true, name2, ""}
-	vendors[name3] = &mockVendor{false, name3, ""}
+	vendors[name1] = &mockVendor{available: false, name: name1}
+	vendors[name2] = &mockVendor{available: true, name: name2}
+	vendors[name3] = &mockVendor{available: false, name: name3}
 
 	availableVendor, err := availableVendorFrom(vendors)
 	require.NoError(t, err)
@@ -51,9 +63,9 @@ func TestUnvailableVendor(t *testing.T) {
 		name3 = "vendor3"
 	)
 	vendors := make(map[string]Vendor)
-	vendors[name1] = &mockVendor{false, name1, ""}
-	vendors[name2] = &mockVendor{false, name2, ""}
-	vendors[name3] = &mockVendor{false, name3, ""}
+	vendors[name1] = &mockVendor{available: false, name: name1}
+	vendors[name2] = &mockVendor{available: false, name: name2}
+	vendors[name3] = &mockVendor{available: false, name: name3}
 
 	_, err := availableVendorFrom(vendors)
 	require.EqualError(t, err, "The cloud vendor couldn't be detected")